@@ -0,0 +1,135 @@
+package modules
+
+import (
+	"sync"
+)
+
+// AlertSeverity describes how severe an alert is. Severities are ordered
+// from least to most severe so that callers can filter or sort on them.
+type AlertSeverity uint8
+
+const (
+	// SeverityUnknown is the default severity for an alert that was
+	// constructed without an explicit severity.
+	SeverityUnknown AlertSeverity = iota
+
+	// SeverityInfo indicates that the condition is worth surfacing to an
+	// operator but does not require any action.
+	SeverityInfo
+
+	// SeverityWarning indicates a condition that could develop into a
+	// problem if left unaddressed.
+	SeverityWarning
+
+	// SeverityError indicates a condition that is actively degrading the
+	// module's ability to function correctly.
+	SeverityError
+
+	// SeverityCritical indicates a condition that requires immediate
+	// operator attention.
+	SeverityCritical
+)
+
+// String returns the human-readable name of an AlertSeverity.
+func (s AlertSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// AlertID uniquely identifies an alert within the module that registered
+// it. Registering an alert with an AlertID that is already known replaces
+// the existing alert rather than creating a duplicate, and unregistering it
+// is idempotent, which allows a module to simply re-register or clear an
+// alert every time it reevaluates the condition that alert tracks.
+type AlertID string
+
+// Alert is a value describing a non-fatal condition that a module wants to
+// surface to an operator, such as low remaining storage on a host or a
+// wallet that is locked while the consensus set is syncing.
+type Alert struct {
+	// Message is a human-readable description of the condition.
+	Message string `json:"message"`
+
+	// Cause is a human-readable explanation of what triggered the alert, if
+	// known. It may be empty.
+	Cause string `json:"cause"`
+
+	// Module is the name of the module that registered the alert, e.g.
+	// "host" or "wallet".
+	Module string `json:"module"`
+
+	// Severity indicates how serious the condition is.
+	Severity AlertSeverity `json:"severity"`
+}
+
+// Alerter is implemented by any module that wants to surface non-fatal
+// conditions to an operator in a uniform way.
+type Alerter interface {
+	// Alerts returns the alerts that are currently registered with the
+	// module.
+	Alerts() []Alert
+}
+
+// GenericAlerter is a thread-safe implementation of the Alerter interface
+// that can be embedded by modules instead of reimplementing alert
+// bookkeeping. Alerts are keyed by an AlertID, so registering the same ID
+// again updates the alert in place and unregistering an ID that was never
+// registered is a no-op.
+type GenericAlerter struct {
+	alerts map[AlertID]Alert
+	module string
+	mu     sync.Mutex
+}
+
+// NewAlerter creates a new GenericAlerter for the module with the given
+// name. The name is attached to every alert registered through it.
+func NewAlerter(module string) *GenericAlerter {
+	return &GenericAlerter{
+		alerts: make(map[AlertID]Alert),
+		module: module,
+	}
+}
+
+// RegisterAlert registers an alert with the given id, message, cause, and
+// severity. If an alert with the same id is already registered, it is
+// overwritten.
+func (a *GenericAlerter) RegisterAlert(id AlertID, message, cause string, severity AlertSeverity) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.alerts[id] = Alert{
+		Message:  message,
+		Cause:    cause,
+		Module:   a.module,
+		Severity: severity,
+	}
+}
+
+// UnregisterAlert removes the alert with the given id, if one is
+// registered. Unregistering an unknown id is a no-op, so callers can
+// unconditionally clear a condition once it resolves.
+func (a *GenericAlerter) UnregisterAlert(id AlertID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.alerts, id)
+}
+
+// Alerts returns all of the alerts that are currently registered.
+func (a *GenericAlerter) Alerts() []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alerts := make([]Alert, 0, len(a.alerts))
+	for _, alert := range a.alerts {
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
@@ -0,0 +1,46 @@
+package modules
+
+import (
+	"testing"
+)
+
+// TestGenericAlerterRegisterUnregister checks that registering an alert
+// twice under the same ID overwrites it rather than creating a duplicate,
+// and that unregistering an ID - known or unknown - is idempotent.
+func TestGenericAlerterRegisterUnregister(t *testing.T) {
+	a := NewAlerter("test")
+
+	a.RegisterAlert("low-storage", "running low on storage", "cause 1", SeverityWarning)
+	alerts := a.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Severity != SeverityWarning || alerts[0].Cause != "cause 1" {
+		t.Fatalf("unexpected alert contents: %+v", alerts[0])
+	}
+
+	// Registering the same ID again should overwrite the existing alert
+	// rather than add a second one.
+	a.RegisterAlert("low-storage", "still running low on storage", "cause 2", SeverityError)
+	alerts = a.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected registering the same ID to overwrite, got %d alerts", len(alerts))
+	}
+	if alerts[0].Severity != SeverityError || alerts[0].Cause != "cause 2" {
+		t.Fatalf("expected the overwritten alert, got %+v", alerts[0])
+	}
+
+	// Unregistering a known ID should remove it.
+	a.UnregisterAlert("low-storage")
+	if len(a.Alerts()) != 0 {
+		t.Fatal("expected UnregisterAlert to remove the alert")
+	}
+
+	// Unregistering an unknown ID, or the same ID twice, should be a no-op
+	// rather than panicking or erroring.
+	a.UnregisterAlert("low-storage")
+	a.UnregisterAlert("never-registered")
+	if len(a.Alerts()) != 0 {
+		t.Fatal("expected repeated/unknown UnregisterAlert calls to be no-ops")
+	}
+}
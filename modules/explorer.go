@@ -0,0 +1,79 @@
+package modules
+
+import (
+	"github.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// ExplorerDir names the directory that contains the explorer's
+	// persistent index.
+	ExplorerDir = "explorer"
+)
+
+type (
+	// BlockFacts returns a set of statistics about the consensus set as it
+	// stood at a given block height, aggregated from the blocks and
+	// transactions the explorer has indexed up to that point.
+	BlockFacts struct {
+		BlockID   types.BlockID     `json:"blockid"`
+		Height    types.BlockHeight `json:"height"`
+		Timestamp types.Timestamp   `json:"timestamp"`
+
+		// ActiveContractCount is the number of file contracts that have
+		// been formed but not yet resolved.
+		ActiveContractCount uint64 `json:"activecontractcount"`
+
+		// TotalContractCollateral is the sum of the host's valid proof
+		// output across every active file contract. That output is counted
+		// as the host's collateral; it is not separated from the rest of
+		// the host's payout.
+		TotalContractCollateral types.Currency `json:"totalcontractcollateral"`
+
+		// TotalSiacoinSupply is the number of siacoins in circulation.
+		TotalSiacoinSupply types.Currency `json:"totalsiacoinsupply"`
+
+		// AverageHostSettings is the average of the HostSettings sampled
+		// from every host announcement seen up to this block.
+		AverageHostSettings HostSettings `json:"averagehostsettings"`
+	}
+
+	// Explorer tracks the full history of the blockchain, maintaining an
+	// index of everything that has happened on the chain so that it can be
+	// queried without replaying the chain from genesis.
+	Explorer interface {
+		// Transaction returns the block height and block that a
+		// transaction was confirmed in, and false if the transaction has
+		// not been seen on the blockchain.
+		Transaction(id types.TransactionID) (types.Block, types.BlockHeight, bool)
+
+		// UnlockHashHistory returns every transaction that has touched the
+		// given unlock hash, ordered from earliest to latest.
+		UnlockHashHistory(uh types.UnlockHash) []types.TransactionID
+
+		// SiacoinOutputHistory returns every transaction that has touched
+		// the given siacoin output, whether by creating it or spending
+		// it, ordered from earliest to latest.
+		SiacoinOutputHistory(id types.SiacoinOutputID) []types.TransactionID
+
+		// FileContractHistory returns the file contract as it was formed,
+		// and the IDs of the transaction that formed it and of every
+		// revision and storage proof transaction that has affected it
+		// since.
+		FileContractHistory(fcid types.FileContractID) (fc types.FileContract, history []types.TransactionID)
+
+		// BlockFacts returns a set of statistics about the consensus set
+		// as they stood at the given height, and false if the explorer has
+		// not indexed a block at that height.
+		BlockFacts(height types.BlockHeight) (BlockFacts, bool)
+
+		// ReceiveConsensusSetUpdate is called by the consensus set every
+		// time the consensus set changes, and is used by the explorer to
+		// keep its index up to date. A fresh explorer replays every change
+		// since genesis to build its index, so existing deployments can
+		// enable the explorer without a full resync of the consensus set.
+		ReceiveConsensusSetUpdate(cc ConsensusChange)
+
+		// Close closes the explorer's persistent index.
+		Close() error
+	}
+)
@@ -0,0 +1,190 @@
+package explorer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// Explorer implements modules.Explorer, maintaining a persistent, bucketed
+// index of the blocks and transactions in the consensus set so that
+// lookups by unlock hash, file contract, or block height do not require
+// replaying the chain.
+type Explorer struct {
+	db *bolt.DB
+	cs modules.ConsensusSet
+
+	mu sync.RWMutex
+}
+
+// Ensure that Explorer satisfies the modules.Explorer interface.
+var _ modules.Explorer = (*Explorer)(nil)
+
+// New creates an Explorer, opening its persistent index in persistDir and
+// subscribing it to cs. If the index does not yet exist, the explorer
+// subscribes from modules.ConsensusChangeBeginning so that it replays the
+// entire chain from genesis; if it already exists, the explorer resumes
+// from the last consensus change it applied, so enabling the explorer on
+// an existing node never requires a full resync of the consensus set.
+func New(cs modules.ConsensusSet, persistDir string) (*Explorer, error) {
+	if cs == nil {
+		return nil, errors.New("explorer cannot be created without a consensus set")
+	}
+	if err := os.MkdirAll(persistDir, 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(filepath.Join(persistDir, dbFilename), 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	e := &Explorer{
+		db: db,
+		cs: cs,
+	}
+
+	ccID := modules.ConsensusChangeBeginning
+	err = db.View(func(tx *bolt.Tx) error {
+		if id, ok := recentChangeID(tx); ok {
+			ccID = id
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := cs.ConsensusSetSubscribe(e, ccID); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Close unsubscribes the explorer from the consensus set and closes its
+// persistent index. Unsubscribing first ensures the consensus set cannot
+// call ReceiveConsensusSetUpdate on an explorer whose db is already closed.
+func (e *Explorer) Close() error {
+	e.cs.Unsubscribe(e)
+	return e.db.Close()
+}
+
+// Transaction returns the block that confirmed the transaction with the
+// given id, the height of that block, and whether the transaction was
+// found.
+func (e *Explorer) Transaction(id types.TransactionID) (block types.Block, height types.BlockHeight, exists bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_ = e.db.View(func(tx *bolt.Tx) error {
+		lookupBytes := tx.Bucket(bucketTransactions).Get(id[:])
+		if lookupBytes == nil {
+			return nil
+		}
+		var lookup transactionLookup
+		if err := decode(lookupBytes, &lookup); err != nil {
+			return err
+		}
+		block, height, exists = lookup.Block, lookup.Height, true
+		return nil
+	})
+	return block, height, exists
+}
+
+// UnlockHashHistory returns every transaction that has touched uh, ordered
+// from earliest to latest.
+func (e *Explorer) UnlockHashHistory(uh types.UnlockHash) []types.TransactionID {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var history []types.TransactionID
+	_ = e.db.View(func(tx *bolt.Tx) error {
+		historyBytes := tx.Bucket(bucketUnlockHashes).Get(uh[:])
+		if historyBytes == nil {
+			return nil
+		}
+		return decode(historyBytes, &history)
+	})
+	return history
+}
+
+// SiacoinOutputHistory returns every transaction that has touched the
+// siacoin output with the given id, whether by creating it or spending
+// it, ordered from earliest to latest.
+func (e *Explorer) SiacoinOutputHistory(id types.SiacoinOutputID) []types.TransactionID {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var history []types.TransactionID
+	_ = e.db.View(func(tx *bolt.Tx) error {
+		historyBytes := tx.Bucket(bucketSiacoinOutputs).Get(id[:])
+		if historyBytes == nil {
+			return nil
+		}
+		return decode(historyBytes, &history)
+	})
+	return history
+}
+
+// FileContractHistory returns the formation contract for fcid and the IDs
+// of the transaction that formed it and of every transaction that has since
+// revised or resolved it.
+func (e *Explorer) FileContractHistory(fcid types.FileContractID) (fc types.FileContract, history []types.TransactionID) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_ = e.db.View(func(tx *bolt.Tx) error {
+		fcBytes := tx.Bucket(bucketFileContracts).Get(fcid[:])
+		if fcBytes == nil {
+			return nil
+		}
+		var fch fileContractHistory
+		if err := decode(fcBytes, &fch); err != nil {
+			return err
+		}
+		fc, history = fch.FileContract, fch.History
+		return nil
+	})
+	return fc, history
+}
+
+// BlockFacts returns the aggregate statistics computed as of the block at
+// height, and false if the explorer has not indexed a block at that
+// height.
+func (e *Explorer) BlockFacts(height types.BlockHeight) (facts modules.BlockFacts, exists bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_ = e.db.View(func(tx *bolt.Tx) error {
+		factsBytes := tx.Bucket(bucketBlockFacts).Get(heightKey(height))
+		if factsBytes == nil {
+			return nil
+		}
+		if err := decode(factsBytes, &facts); err != nil {
+			return err
+		}
+		exists = true
+		return nil
+	})
+	return facts, exists
+}
@@ -0,0 +1,209 @@
+package explorer
+
+import (
+	"encoding/binary"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// dbFilename is the name of the bolt database that backs the explorer's
+// index.
+const dbFilename = "explorer.db"
+
+var (
+	// bucketMeta stores the explorer's own bookkeeping: the height it has
+	// indexed up to and the ID of the last consensus change it applied.
+	bucketMeta = []byte("Meta")
+
+	// bucketBlockFacts maps a block height to the aggregate BlockFacts
+	// computed as of that block.
+	bucketBlockFacts = []byte("BlockFacts")
+
+	// bucketBlockHeights maps a BlockID to the height it was mined at.
+	bucketBlockHeights = []byte("BlockHeights")
+
+	// bucketTransactions maps a TransactionID to the block that confirmed
+	// it, so that a transaction can be looked up without replaying the
+	// chain.
+	bucketTransactions = []byte("Transactions")
+
+	// bucketUnlockHashes maps an UnlockHash to the IDs of every
+	// transaction that has touched it.
+	bucketUnlockHashes = []byte("UnlockHashes")
+
+	// bucketSiacoinOutputs maps a SiacoinOutputID to the IDs of every
+	// transaction that has touched it, whether by creating it or by
+	// spending it.
+	bucketSiacoinOutputs = []byte("SiacoinOutputs")
+
+	// bucketFileContracts maps a FileContractID to its formation contract
+	// and the IDs of the transaction that formed it and of every
+	// transaction that has since revised or resolved it.
+	bucketFileContracts = []byte("FileContracts")
+
+	// bucketContractExpirations maps a (WindowEnd height, FileContractID)
+	// pair to nothing, and is used to look up which contracts are due to
+	// expire at a given height without scanning every active contract.
+	bucketContractExpirations = []byte("ContractExpirations")
+
+	// bucketDeactivatedContracts maps a block height to the IDs of every
+	// file contract that left bucketContractExpirations at that height,
+	// whether because its expiration window closed or because a storage
+	// proof resolved it, so that reverting the block can restore exactly
+	// those entries.
+	bucketDeactivatedContracts = []byte("DeactivatedContracts")
+)
+
+var (
+	// keyHeight is the bucketMeta key that stores the height the explorer
+	// has indexed up to.
+	keyHeight = []byte("Height")
+
+	// keyRecentChangeID is the bucketMeta key that stores the ID of the
+	// last consensus change the explorer applied, so that it can resume
+	// from that point instead of replaying from genesis on every restart.
+	keyRecentChangeID = []byte("RecentChangeID")
+
+	// keyTotalSupply is the bucketMeta key that stores the running total
+	// of siacoins minted by block subsidies up to the indexed height.
+	keyTotalSupply = []byte("TotalSupply")
+
+	// keyHostSettingsTotals is the bucketMeta key that stores the running
+	// totals used to compute BlockFacts.AverageHostSettings.
+	keyHostSettingsTotals = []byte("HostSettingsTotals")
+)
+
+// buckets lists every bucket the explorer's database uses, so that they can
+// all be created together when the database is first opened.
+var buckets = [][]byte{
+	bucketMeta,
+	bucketBlockFacts,
+	bucketBlockHeights,
+	bucketTransactions,
+	bucketUnlockHashes,
+	bucketSiacoinOutputs,
+	bucketFileContracts,
+	bucketContractExpirations,
+	bucketDeactivatedContracts,
+}
+
+// heightKey returns the sortable, big-endian encoded key used to index a
+// block height.
+func heightKey(height types.BlockHeight) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+	return key
+}
+
+// expirationKey returns the key used to record that fcid is due to expire
+// at height, ordered so that every contract expiring at a given height can
+// be found by scanning a single key prefix.
+func expirationKey(height types.BlockHeight, fcid types.FileContractID) []byte {
+	key := make([]byte, 8+len(fcid))
+	binary.BigEndian.PutUint64(key, uint64(height))
+	copy(key[8:], fcid[:])
+	return key
+}
+
+// recentChangeID returns the ID of the last consensus change the explorer
+// applied, and false if the explorer has never processed a change.
+func recentChangeID(tx *bolt.Tx) (id modules.ConsensusChangeID, ok bool) {
+	idBytes := tx.Bucket(bucketMeta).Get(keyRecentChangeID)
+	if idBytes == nil {
+		return modules.ConsensusChangeID{}, false
+	}
+	copy(id[:], idBytes)
+	return id, true
+}
+
+// setRecentChangeID persists the ID of the last consensus change the
+// explorer applied.
+func setRecentChangeID(tx *bolt.Tx, id modules.ConsensusChangeID) error {
+	return tx.Bucket(bucketMeta).Put(keyRecentChangeID, id[:])
+}
+
+// blockHeight returns the height the explorer has indexed up to.
+func blockHeight(tx *bolt.Tx) types.BlockHeight {
+	heightBytes := tx.Bucket(bucketMeta).Get(keyHeight)
+	if heightBytes == nil {
+		return 0
+	}
+	return types.BlockHeight(binary.BigEndian.Uint64(heightBytes))
+}
+
+// setBlockHeight persists the height the explorer has indexed up to.
+func setBlockHeight(tx *bolt.Tx, height types.BlockHeight) error {
+	return tx.Bucket(bucketMeta).Put(keyHeight, heightKey(height))
+}
+
+// totalSupply returns the running total of siacoins minted by block
+// subsidies up to the indexed height.
+func totalSupply(tx *bolt.Tx) types.Currency {
+	supplyBytes := tx.Bucket(bucketMeta).Get(keyTotalSupply)
+	if supplyBytes == nil {
+		return types.Currency{}
+	}
+	var supply types.Currency
+	if err := decode(supplyBytes, &supply); err != nil {
+		return types.Currency{}
+	}
+	return supply
+}
+
+// setTotalSupply persists the running total of siacoins minted by block
+// subsidies up to the indexed height.
+func setTotalSupply(tx *bolt.Tx, supply types.Currency) error {
+	return tx.Bucket(bucketMeta).Put(keyTotalSupply, encode(supply))
+}
+
+// hostSettingsTotals accumulates the price fields of every HostSettings
+// sampled from a host announcement, along with a count, so that
+// BlockFacts.AverageHostSettings can be computed as a running average
+// without rescanning every announcement seen so far.
+type hostSettingsTotals struct {
+	Count uint64
+
+	Collateral             types.Currency
+	ContractPrice          types.Currency
+	DownloadBandwidthPrice types.Currency
+	StoragePrice           types.Currency
+	UploadBandwidthPrice   types.Currency
+}
+
+// hostSettingsTotal returns the running totals used to compute
+// BlockFacts.AverageHostSettings.
+func hostSettingsTotal(tx *bolt.Tx) hostSettingsTotals {
+	totalsBytes := tx.Bucket(bucketMeta).Get(keyHostSettingsTotals)
+	if totalsBytes == nil {
+		return hostSettingsTotals{}
+	}
+	var totals hostSettingsTotals
+	if err := decode(totalsBytes, &totals); err != nil {
+		return hostSettingsTotals{}
+	}
+	return totals
+}
+
+// setHostSettingsTotal persists the running totals used to compute
+// BlockFacts.AverageHostSettings.
+func setHostSettingsTotal(tx *bolt.Tx, totals hostSettingsTotals) error {
+	return tx.Bucket(bucketMeta).Put(keyHostSettingsTotals, encode(totals))
+}
+
+// average returns the average of every HostSettings sampled so far, or the
+// zero value if none have been sampled.
+func (t hostSettingsTotals) average() (avg modules.HostSettings) {
+	if t.Count == 0 {
+		return modules.HostSettings{}
+	}
+	n := types.NewCurrency64(t.Count)
+	avg.Collateral = t.Collateral.Div(n)
+	avg.ContractPrice = t.ContractPrice.Div(n)
+	avg.DownloadBandwidthPrice = t.DownloadBandwidthPrice.Div(n)
+	avg.StoragePrice = t.StoragePrice.Div(n)
+	avg.UploadBandwidthPrice = t.UploadBandwidthPrice.Div(n)
+	return avg
+}
@@ -0,0 +1,511 @@
+package explorer
+
+import (
+	"bytes"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+type (
+	// transactionLookup is the value stored in bucketTransactions: enough
+	// information to answer Transaction(id) without replaying the chain.
+	transactionLookup struct {
+		Block  types.Block
+		Height types.BlockHeight
+	}
+
+	// fileContractHistory is the value stored in bucketFileContracts.
+	fileContractHistory struct {
+		FileContract types.FileContract
+		History      []types.TransactionID
+	}
+)
+
+// encode and decode wrap the encoding package's binary marshaling so that
+// the rest of the package can persist arbitrary values without repeating
+// the error handling.
+func encode(v interface{}) []byte {
+	return encoding.Marshal(v)
+}
+
+func decode(b []byte, v interface{}) error {
+	return encoding.Unmarshal(b, v)
+}
+
+// ReceiveConsensusSetUpdate implements modules.Explorer, applying the
+// reverted and applied blocks in cc to the persistent index.
+func (e *Explorer) ReceiveConsensusSetUpdate(cc modules.ConsensusChange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.db.Update(func(tx *bolt.Tx) error {
+		height := blockHeight(tx)
+		supply := totalSupply(tx)
+		for _, block := range cc.RevertedBlocks {
+			if err := e.revertBlock(tx, block, height); err != nil {
+				return err
+			}
+			supply = supply.Sub(types.CalculateCoinbase(height))
+			height--
+		}
+		for _, block := range cc.AppliedBlocks {
+			height++
+			supply = supply.Add(types.CalculateCoinbase(height))
+			if err := e.applyBlock(tx, block, height, supply); err != nil {
+				return err
+			}
+		}
+		if err := setBlockHeight(tx, height); err != nil {
+			return err
+		}
+		if err := setTotalSupply(tx, supply); err != nil {
+			return err
+		}
+		return setRecentChangeID(tx, cc.ID)
+	})
+	if err != nil {
+		build.Critical("explorer failed to apply consensus change:", err)
+	}
+}
+
+// applyBlock adds block, mined at height, to the persistent index.
+func (e *Explorer) applyBlock(tx *bolt.Tx, block types.Block, height types.BlockHeight, supply types.Currency) error {
+	blockID := block.ID()
+	if err := tx.Bucket(bucketBlockHeights).Put(blockID[:], heightKey(height)); err != nil {
+		return err
+	}
+
+	for _, txn := range block.Transactions {
+		if err := e.indexTransaction(tx, block, height, txn); err != nil {
+			return err
+		}
+	}
+
+	if err := e.expireContracts(tx, height); err != nil {
+		return err
+	}
+
+	return e.putBlockFacts(tx, block, height, supply)
+}
+
+// revertBlock removes block, previously mined at height, from the
+// persistent index, undoing every mutation applyBlock made for it.
+func (e *Explorer) revertBlock(tx *bolt.Tx, block types.Block, height types.BlockHeight) error {
+	if err := tx.Bucket(bucketBlockFacts).Delete(heightKey(height)); err != nil {
+		return err
+	}
+
+	if err := e.reactivateContracts(tx, height); err != nil {
+		return err
+	}
+
+	for i := len(block.Transactions) - 1; i >= 0; i-- {
+		if err := e.revertTransaction(tx, height, block.Transactions[i]); err != nil {
+			return err
+		}
+	}
+
+	blockID := block.ID()
+	return tx.Bucket(bucketBlockHeights).Delete(blockID[:])
+}
+
+// indexTransaction records every unlock hash, siacoin output, file
+// contract, and host announcement that txn touches.
+func (e *Explorer) indexTransaction(tx *bolt.Tx, block types.Block, height types.BlockHeight, txn types.Transaction) error {
+	txnID := txn.ID()
+	lookup := transactionLookup{Block: block, Height: height}
+	if err := tx.Bucket(bucketTransactions).Put(txnID[:], encode(lookup)); err != nil {
+		return err
+	}
+
+	for _, uh := range relatedUnlockHashes(txn) {
+		if err := appendHistory(tx, bucketUnlockHashes, uh[:], txnID); err != nil {
+			return err
+		}
+	}
+
+	for _, scoid := range relatedSiacoinOutputIDs(txn) {
+		if err := appendHistory(tx, bucketSiacoinOutputs, scoid[:], txnID); err != nil {
+			return err
+		}
+	}
+
+	for i, fc := range txn.FileContracts {
+		fcid := txn.FileContractID(uint64(i))
+		if err := e.formContract(tx, fcid, fc, txnID); err != nil {
+			return err
+		}
+	}
+
+	for _, fcr := range txn.FileContractRevisions {
+		if err := e.appendFileContractHistory(tx, fcr.ParentID, txnID); err != nil {
+			return err
+		}
+	}
+
+	for _, sp := range txn.StorageProofs {
+		if err := e.appendFileContractHistory(tx, sp.ParentID, txnID); err != nil {
+			return err
+		}
+		if err := e.resolveContract(tx, sp.ParentID, height); err != nil {
+			return err
+		}
+	}
+
+	for _, ad := range txn.ArbitraryData {
+		ha, ok := decodeHostAnnouncement(ad)
+		if !ok {
+			continue
+		}
+		totals := hostSettingsTotal(tx)
+		totals.Count++
+		totals.Collateral = totals.Collateral.Add(ha.Settings.Collateral)
+		totals.ContractPrice = totals.ContractPrice.Add(ha.Settings.ContractPrice)
+		totals.DownloadBandwidthPrice = totals.DownloadBandwidthPrice.Add(ha.Settings.DownloadBandwidthPrice)
+		totals.StoragePrice = totals.StoragePrice.Add(ha.Settings.StoragePrice)
+		totals.UploadBandwidthPrice = totals.UploadBandwidthPrice.Add(ha.Settings.UploadBandwidthPrice)
+		if err := setHostSettingsTotal(tx, totals); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revertTransaction undoes every mutation indexTransaction made for txn,
+// which was previously confirmed at height, in the reverse order they were
+// applied.
+func (e *Explorer) revertTransaction(tx *bolt.Tx, height types.BlockHeight, txn types.Transaction) error {
+	for _, ad := range txn.ArbitraryData {
+		ha, ok := decodeHostAnnouncement(ad)
+		if !ok {
+			continue
+		}
+		totals := hostSettingsTotal(tx)
+		totals.Count--
+		totals.Collateral = totals.Collateral.Sub(ha.Settings.Collateral)
+		totals.ContractPrice = totals.ContractPrice.Sub(ha.Settings.ContractPrice)
+		totals.DownloadBandwidthPrice = totals.DownloadBandwidthPrice.Sub(ha.Settings.DownloadBandwidthPrice)
+		totals.StoragePrice = totals.StoragePrice.Sub(ha.Settings.StoragePrice)
+		totals.UploadBandwidthPrice = totals.UploadBandwidthPrice.Sub(ha.Settings.UploadBandwidthPrice)
+		if err := setHostSettingsTotal(tx, totals); err != nil {
+			return err
+		}
+	}
+
+	txnID := txn.ID()
+	for i := len(txn.StorageProofs) - 1; i >= 0; i-- {
+		sp := txn.StorageProofs[i]
+		if err := removeLastFileContractHistory(tx, sp.ParentID); err != nil {
+			return err
+		}
+	}
+
+	for i := len(txn.FileContractRevisions) - 1; i >= 0; i-- {
+		fcr := txn.FileContractRevisions[i]
+		if err := removeLastFileContractHistory(tx, fcr.ParentID); err != nil {
+			return err
+		}
+	}
+
+	for i := len(txn.FileContracts) - 1; i >= 0; i-- {
+		fcid := txn.FileContractID(uint64(i))
+		if err := e.unformContract(tx, fcid); err != nil {
+			return err
+		}
+	}
+
+	scoids := relatedSiacoinOutputIDs(txn)
+	for i := len(scoids) - 1; i >= 0; i-- {
+		if err := removeLastHistory(tx, bucketSiacoinOutputs, scoids[i][:]); err != nil {
+			return err
+		}
+	}
+
+	uhs := relatedUnlockHashes(txn)
+	for i := len(uhs) - 1; i >= 0; i-- {
+		if err := removeLastHistory(tx, bucketUnlockHashes, uhs[i][:]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(bucketTransactions).Delete(txnID[:])
+}
+
+// relatedUnlockHashes returns every unlock hash that txn's siacoin inputs
+// and outputs touch.
+func relatedUnlockHashes(txn types.Transaction) []types.UnlockHash {
+	var hashes []types.UnlockHash
+	for _, sci := range txn.SiacoinInputs {
+		hashes = append(hashes, sci.UnlockConditions.UnlockHash())
+	}
+	for _, sco := range txn.SiacoinOutputs {
+		hashes = append(hashes, sco.UnlockHash)
+	}
+	return hashes
+}
+
+// relatedSiacoinOutputIDs returns the ID of every siacoin output that txn
+// creates or spends.
+func relatedSiacoinOutputIDs(txn types.Transaction) []types.SiacoinOutputID {
+	var ids []types.SiacoinOutputID
+	for _, sci := range txn.SiacoinInputs {
+		ids = append(ids, sci.ParentID)
+	}
+	for i := range txn.SiacoinOutputs {
+		ids = append(ids, txn.SiacoinOutputID(uint64(i)))
+	}
+	return ids
+}
+
+// appendHistory records that txnID touched the entry stored under key in
+// bucket.
+func appendHistory(tx *bolt.Tx, bucket []byte, key []byte, txnID types.TransactionID) error {
+	b := tx.Bucket(bucket)
+	var history []types.TransactionID
+	if existing := b.Get(key); existing != nil {
+		if err := decode(existing, &history); err != nil {
+			return err
+		}
+	}
+	history = append(history, txnID)
+	return b.Put(key, encode(history))
+}
+
+// removeLastHistory removes the most recently appended entry from the
+// history stored under key in bucket, deleting the key entirely once its
+// history is empty.
+func removeLastHistory(tx *bolt.Tx, bucket []byte, key []byte) error {
+	b := tx.Bucket(bucket)
+	existing := b.Get(key)
+	if existing == nil {
+		return nil
+	}
+	var history []types.TransactionID
+	if err := decode(existing, &history); err != nil {
+		return err
+	}
+	if len(history) <= 1 {
+		return b.Delete(key)
+	}
+	history = history[:len(history)-1]
+	return b.Put(key, encode(history))
+}
+
+// appendFileContractHistory records that txnID revised or resolved fcid.
+func (e *Explorer) appendFileContractHistory(tx *bolt.Tx, fcid types.FileContractID, txnID types.TransactionID) error {
+	bucket := tx.Bucket(bucketFileContracts)
+	existing := bucket.Get(fcid[:])
+	if existing == nil {
+		// The contract was formed before the explorer started indexing;
+		// there is nothing to append a revision onto.
+		return nil
+	}
+	var fch fileContractHistory
+	if err := decode(existing, &fch); err != nil {
+		return err
+	}
+	fch.History = append(fch.History, txnID)
+	return bucket.Put(fcid[:], encode(fch))
+}
+
+// removeLastFileContractHistory removes the most recently appended
+// revision or storage-proof entry from fcid's history, undoing
+// appendFileContractHistory.
+func removeLastFileContractHistory(tx *bolt.Tx, fcid types.FileContractID) error {
+	bucket := tx.Bucket(bucketFileContracts)
+	existing := bucket.Get(fcid[:])
+	if existing == nil {
+		return nil
+	}
+	var fch fileContractHistory
+	if err := decode(existing, &fch); err != nil {
+		return err
+	}
+	if len(fch.History) == 0 {
+		return nil
+	}
+	fch.History = fch.History[:len(fch.History)-1]
+	return bucket.Put(fcid[:], encode(fch))
+}
+
+// formContract records fc as the formation contract for fcid, and marks it
+// as due to expire at fc.WindowEnd.
+func (e *Explorer) formContract(tx *bolt.Tx, fcid types.FileContractID, fc types.FileContract, txnID types.TransactionID) error {
+	fch := fileContractHistory{FileContract: fc, History: []types.TransactionID{txnID}}
+	if err := tx.Bucket(bucketFileContracts).Put(fcid[:], encode(fch)); err != nil {
+		return err
+	}
+	return tx.Bucket(bucketContractExpirations).Put(expirationKey(fc.WindowEnd, fcid), []byte{})
+}
+
+// unformContract removes fcid entirely from the index, undoing formContract.
+func (e *Explorer) unformContract(tx *bolt.Tx, fcid types.FileContractID) error {
+	fcBytes := tx.Bucket(bucketFileContracts).Get(fcid[:])
+	if fcBytes == nil {
+		return nil
+	}
+	var fch fileContractHistory
+	if err := decode(fcBytes, &fch); err != nil {
+		return err
+	}
+	if err := tx.Bucket(bucketContractExpirations).Delete(expirationKey(fch.FileContract.WindowEnd, fcid)); err != nil {
+		return err
+	}
+	return tx.Bucket(bucketFileContracts).Delete(fcid[:])
+}
+
+// resolveContract removes fcid from the set of contracts the explorer
+// considers active, and records that it was deactivated at height so that
+// reverting the block can restore it.
+func (e *Explorer) resolveContract(tx *bolt.Tx, fcid types.FileContractID, height types.BlockHeight) error {
+	fcBytes := tx.Bucket(bucketFileContracts).Get(fcid[:])
+	if fcBytes == nil {
+		return nil
+	}
+	var fch fileContractHistory
+	if err := decode(fcBytes, &fch); err != nil {
+		return err
+	}
+	if err := tx.Bucket(bucketContractExpirations).Delete(expirationKey(fch.FileContract.WindowEnd, fcid)); err != nil {
+		return err
+	}
+	return recordDeactivation(tx, height, fcid)
+}
+
+// expireContracts resolves every contract whose expiration window closed
+// at height without a storage proof being submitted, recording each one as
+// deactivated at height so that reverting the block can restore it.
+func (e *Explorer) expireContracts(tx *bolt.Tx, height types.BlockHeight) error {
+	bucket := tx.Bucket(bucketContractExpirations)
+	c := bucket.Cursor()
+	prefix := heightKey(height)
+	var expired [][]byte
+	for k, _ := c.Seek(prefix); k != nil && len(k) >= 8 && string(k[:8]) == string(prefix); k, _ = c.Next() {
+		expired = append(expired, append([]byte{}, k...))
+	}
+	for _, k := range expired {
+		var fcid types.FileContractID
+		copy(fcid[:], k[8:])
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+		if err := recordDeactivation(tx, height, fcid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordDeactivation records that fcid left bucketContractExpirations at
+// height.
+func recordDeactivation(tx *bolt.Tx, height types.BlockHeight, fcid types.FileContractID) error {
+	bucket := tx.Bucket(bucketDeactivatedContracts)
+	key := heightKey(height)
+	var deactivated []types.FileContractID
+	if existing := bucket.Get(key); existing != nil {
+		if err := decode(existing, &deactivated); err != nil {
+			return err
+		}
+	}
+	deactivated = append(deactivated, fcid)
+	return bucket.Put(key, encode(deactivated))
+}
+
+// reactivateContracts restores, into bucketContractExpirations, every
+// contract that recordDeactivation recorded as having left it at height,
+// undoing both expireContracts and resolveContract for that height.
+func (e *Explorer) reactivateContracts(tx *bolt.Tx, height types.BlockHeight) error {
+	bucket := tx.Bucket(bucketDeactivatedContracts)
+	key := heightKey(height)
+	existing := bucket.Get(key)
+	if existing == nil {
+		return nil
+	}
+	var deactivated []types.FileContractID
+	if err := decode(existing, &deactivated); err != nil {
+		return err
+	}
+	for _, fcid := range deactivated {
+		fcBytes := tx.Bucket(bucketFileContracts).Get(fcid[:])
+		if fcBytes == nil {
+			continue
+		}
+		var fch fileContractHistory
+		if err := decode(fcBytes, &fch); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketContractExpirations).Put(expirationKey(fch.FileContract.WindowEnd, fcid), []byte{}); err != nil {
+			return err
+		}
+	}
+	return bucket.Delete(key)
+}
+
+// decodeHostAnnouncement extracts the HostAnnouncement encoded in ad, and
+// returns false if ad is not a host announcement.
+func decodeHostAnnouncement(ad []byte) (ha modules.HostAnnouncement, ok bool) {
+	prefix := modules.PrefixHostAnnouncement[:]
+	if len(ad) < len(prefix) || !bytes.Equal(ad[:len(prefix)], prefix) {
+		return modules.HostAnnouncement{}, false
+	}
+	if err := decode(ad[len(prefix):], &ha); err != nil {
+		return modules.HostAnnouncement{}, false
+	}
+	return ha, true
+}
+
+// hostCollateral returns the host's valid proof output of fc, which the
+// explorer counts as the collateral the host has committed to fc. fc.Payout is the gross, pre-tax amount funding the entire contract
+// (renter payment plus host collateral, grossed up for the siafund tax), so
+// it is always far larger than any single output and cannot be subtracted
+// from the host's payout. The host's valid proof output is the best proxy
+// the explorer has for the collateral at stake without replaying the
+// contract negotiation that produced it.
+func hostCollateral(fc types.FileContract) types.Currency {
+	if len(fc.ValidProofOutputs) < 2 {
+		return types.ZeroCurrency
+	}
+	return fc.ValidProofOutputs[1].Value
+}
+
+// putBlockFacts computes and persists the aggregate statistics for block,
+// mined at height, given the running siacoin supply as of that height.
+func (e *Explorer) putBlockFacts(tx *bolt.Tx, block types.Block, height types.BlockHeight, supply types.Currency) error {
+	facts := modules.BlockFacts{
+		BlockID:   block.ID(),
+		Height:    height,
+		Timestamp: block.Timestamp,
+	}
+
+	var activeContracts uint64
+	var totalCollateral types.Currency
+	err := tx.Bucket(bucketContractExpirations).ForEach(func(k, _ []byte) error {
+		activeContracts++
+		fcid := k[8:]
+		fcBytes := tx.Bucket(bucketFileContracts).Get(fcid)
+		if fcBytes == nil {
+			return nil
+		}
+		var fch fileContractHistory
+		if err := decode(fcBytes, &fch); err != nil {
+			return err
+		}
+		totalCollateral = totalCollateral.Add(hostCollateral(fch.FileContract))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	facts.ActiveContractCount = activeContracts
+	facts.TotalContractCollateral = totalCollateral
+	facts.TotalSiacoinSupply = supply
+	facts.AverageHostSettings = hostSettingsTotal(tx).average()
+
+	return tx.Bucket(bucketBlockFacts).Put(heightKey(height), encode(facts))
+}
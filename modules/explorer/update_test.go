@@ -0,0 +1,153 @@
+package explorer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// newTestExplorer opens an Explorer backed by a temporary bolt database,
+// bypassing New's consensus set subscription so that
+// ReceiveConsensusSetUpdate can be driven directly with hand-built
+// ConsensusChanges.
+func newTestExplorer(t *testing.T) *Explorer {
+	dir, err := ioutil.TempDir("", "explorer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	db, err := bolt.Open(filepath.Join(dir, dbFilename), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Explorer{db: db}
+}
+
+// contractBlock returns a block containing a single transaction that forms
+// a file contract expiring at windowEnd, along with the ID of that
+// contract.
+func contractBlock(windowEnd types.BlockHeight) (types.Block, types.FileContractID) {
+	txn := types.Transaction{
+		FileContracts: []types.FileContract{{
+			WindowEnd: windowEnd,
+			Payout:    types.NewCurrency64(1145).Mul(types.SiacoinPrecision).Div(types.NewCurrency64(10)),
+			ValidProofOutputs: []types.SiacoinOutput{
+				{Value: types.NewCurrency64(100).Mul(types.SiacoinPrecision)},
+				{Value: types.NewCurrency64(11).Mul(types.SiacoinPrecision)},
+			},
+		}},
+	}
+	block := types.Block{Transactions: []types.Transaction{txn}}
+	return block, txn.FileContractID(0)
+}
+
+// TestApplyRevertSymmetry applies a block that forms a file contract, then
+// reverts it, and checks that every bucket the explorer touched - block
+// facts, the file contract index, the running height, and the running
+// supply - ends up back exactly where it started.
+func TestApplyRevertSymmetry(t *testing.T) {
+	e := newTestExplorer(t)
+
+	block, fcid := contractBlock(10)
+	e.ReceiveConsensusSetUpdate(modules.ConsensusChange{AppliedBlocks: []types.Block{block}})
+
+	facts, exists := e.BlockFacts(1)
+	if !exists {
+		t.Fatal("expected BlockFacts for height 1 after applying a block")
+	}
+	if facts.ActiveContractCount != 1 {
+		t.Fatalf("expected 1 active contract, got %d", facts.ActiveContractCount)
+	}
+	wantCollateral := block.Transactions[0].FileContracts[0].ValidProofOutputs[1].Value
+	if facts.TotalContractCollateral.Cmp(wantCollateral) != 0 {
+		t.Fatalf("expected collateral %v, got %v", wantCollateral, facts.TotalContractCollateral)
+	}
+	if facts.TotalSiacoinSupply.Cmp(types.CalculateCoinbase(1)) != 0 {
+		t.Fatalf("expected supply %v, got %v", types.CalculateCoinbase(1), facts.TotalSiacoinSupply)
+	}
+	fc, history := e.FileContractHistory(fcid)
+	if len(history) != 1 {
+		t.Fatalf("expected 1 history entry for the formation contract, got %d", len(history))
+	}
+	if !reflect.DeepEqual(fc, block.Transactions[0].FileContracts[0]) {
+		t.Fatal("indexed file contract does not match the one formed")
+	}
+
+	e.ReceiveConsensusSetUpdate(modules.ConsensusChange{RevertedBlocks: []types.Block{block}})
+
+	if _, exists := e.BlockFacts(1); exists {
+		t.Fatal("expected BlockFacts for height 1 to be removed after reverting")
+	}
+	if fc, history := e.FileContractHistory(fcid); len(history) != 0 || !reflect.DeepEqual(fc, types.FileContract{}) {
+		t.Fatal("expected the file contract to be fully unformed after reverting")
+	}
+
+	err := e.db.View(func(tx *bolt.Tx) error {
+		if height := blockHeight(tx); height != 0 {
+			t.Fatalf("expected height 0 after reverting, got %d", height)
+		}
+		if supply := totalSupply(tx); supply.Cmp(types.ZeroCurrency) != 0 {
+			t.Fatalf("expected supply 0 after reverting, got %v", supply)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestContractExpirationRevert applies a contract that expires at the block
+// immediately following formation, then reverts the expiring block, and
+// checks that the contract is restored to the active set rather than left
+// expired.
+func TestContractExpirationRevert(t *testing.T) {
+	e := newTestExplorer(t)
+
+	formBlock, fcid := contractBlock(2)
+	e.ReceiveConsensusSetUpdate(modules.ConsensusChange{AppliedBlocks: []types.Block{formBlock}})
+
+	expireBlock := types.Block{}
+	e.ReceiveConsensusSetUpdate(modules.ConsensusChange{AppliedBlocks: []types.Block{expireBlock}})
+
+	facts, exists := e.BlockFacts(2)
+	if !exists {
+		t.Fatal("expected BlockFacts for height 2 after applying a block")
+	}
+	if facts.ActiveContractCount != 0 {
+		t.Fatalf("expected the contract to have expired by height 2, got %d active", facts.ActiveContractCount)
+	}
+
+	e.ReceiveConsensusSetUpdate(modules.ConsensusChange{RevertedBlocks: []types.Block{expireBlock}})
+
+	err := e.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(bucketContractExpirations).Get(expirationKey(2, fcid)) == nil {
+			t.Fatal("expected the contract to be restored to bucketContractExpirations after reverting its expiration")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -51,9 +51,12 @@ type (
 	}
 
 	// HostAnnouncement declares a nodes intent to be a host, providing a net
-	// address that can be used to contact the host.
+	// address that can be used to contact the host and the settings it was
+	// advertising at the time of the announcement, so that tools like the
+	// explorer can sample pricing without negotiating a settings RPC.
 	HostAnnouncement struct {
 		IPAddress NetAddress
+		Settings  HostSettings
 	}
 
 	// HostSettings are the parameters advertised by the host. These are the
@@ -91,6 +94,12 @@ type (
 	// such as announcements, settings, and implementing all of the RPCs of the
 	// host protocol.
 	Host interface {
+		// Alerter allows the host to be queried for any alerts it has
+		// registered, such as low RemainingStorage, AcceptingContracts
+		// being false while contracts remain unresolved, or a rise in
+		// ErrorCalls/UnrecognizedCalls in the host's RPC metrics.
+		Alerter
+
 		// Announce submits a host announcement to the blockchain, returning an
 		// error if its external IP address is unknown. After announcing, the
 		// host will begin accepting contracts.
@@ -114,6 +123,12 @@ type (
 		// on the file contract will be lost, and the data will be removed.
 		DeleteContract(types.FileContractID) error
 
+		// FormContract records a newly formed file contract as one the host
+		// is now responsible for, refreshing any alerts that depend on the
+		// host's contract count. It is called by the host's RPC dispatch
+		// once a contract negotiation completes successfully.
+		FormContract(types.FileContractID) error
+
 		// NetAddress returns the host's network address
 		NetAddress() NetAddress
 
@@ -136,6 +151,11 @@ type (
 		// Settings returns the host's settings.
 		Settings() HostSettings
 
+		// TrackRPCCall records the outcome of an RPC call, refreshing any
+		// alerts that depend on the host's RPC metrics. It is called by the
+		// host's RPC dispatch for every call it serves.
+		TrackRPCCall(errored, unrecognized bool)
+
 		// Close saves the state of the host and stops its listener process.
 		Close() error
 	}
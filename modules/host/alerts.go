@@ -0,0 +1,55 @@
+package host
+
+import (
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+const (
+	// lowStorageAlertThreshold is the fraction of TotalStorage that
+	// RemainingStorage must fall below before the host warns an operator
+	// that it may be running low on space.
+	lowStorageAlertThreshold = 0.05
+
+	// errorCallWarningThreshold and errorCallCriticalThreshold are the
+	// number of failed RPC calls (ErrorCalls + UnrecognizedCalls) at which
+	// the host raises a warning or an error, respectively.
+	errorCallWarningThreshold  = 100
+	errorCallCriticalThreshold = 1000
+)
+
+const (
+	alertIDLowStorage            = modules.AlertID("host-low-remaining-storage")
+	alertIDNotAcceptingContracts = modules.AlertID("host-not-accepting-contracts")
+	alertIDRPCErrors             = modules.AlertID("host-rpc-errors")
+)
+
+// updateAlerts recalculates every alert the host tracks from its current
+// settings, RPC metrics, and contract count. It is called any time one of
+// those values changes.
+func (h *Host) updateAlerts() {
+	// RemainingStorage has dropped below the warning threshold.
+	if h.settings.TotalStorage > 0 && h.settings.RemainingStorage < uint64(float64(h.settings.TotalStorage)*lowStorageAlertThreshold) {
+		h.alerts.RegisterAlert(alertIDLowStorage, "the host is running low on storage", "RemainingStorage has dropped below 5% of TotalStorage", modules.SeverityWarning)
+	} else {
+		h.alerts.UnregisterAlert(alertIDLowStorage)
+	}
+
+	// The host has stopped accepting contracts but still has unresolved
+	// contracts to honor.
+	if !h.settings.AcceptingContracts && len(h.contracts) > 0 {
+		h.alerts.RegisterAlert(alertIDNotAcceptingContracts, "the host is not accepting new contracts", "AcceptingContracts is false while unresolved contracts remain", modules.SeverityInfo)
+	} else {
+		h.alerts.UnregisterAlert(alertIDNotAcceptingContracts)
+	}
+
+	// RPC calls are failing more often than expected.
+	failedCalls := h.metrics.ErrorCalls + h.metrics.UnrecognizedCalls
+	switch {
+	case failedCalls >= errorCallCriticalThreshold:
+		h.alerts.RegisterAlert(alertIDRPCErrors, "the host is seeing a high rate of failed RPC calls", "ErrorCalls and UnrecognizedCalls have exceeded the critical threshold", modules.SeverityError)
+	case failedCalls >= errorCallWarningThreshold:
+		h.alerts.RegisterAlert(alertIDRPCErrors, "the host is seeing failed RPC calls", "ErrorCalls and UnrecognizedCalls have exceeded the warning threshold", modules.SeverityWarning)
+	default:
+		h.alerts.UnregisterAlert(alertIDRPCErrors)
+	}
+}
@@ -0,0 +1,99 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestHostLowStorageAlert checks that the low-storage alert is raised when
+// RemainingStorage drops below the threshold, and cleared when it recovers.
+func TestHostLowStorageAlert(t *testing.T) {
+	h := New()
+
+	err := h.SetSettings(modules.HostSettings{TotalStorage: 100, RemainingStorage: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 0 {
+		t.Fatalf("expected no alerts at 50%% remaining storage, got %d", len(h.Alerts()))
+	}
+
+	err = h.SetSettings(modules.HostSettings{TotalStorage: 100, RemainingStorage: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 1 {
+		t.Fatalf("expected a low-storage alert at 1%% remaining storage, got %d alerts", len(h.Alerts()))
+	}
+
+	err = h.SetSettings(modules.HostSettings{TotalStorage: 100, RemainingStorage: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 0 {
+		t.Fatal("expected the low-storage alert to clear once RemainingStorage recovered")
+	}
+}
+
+// TestHostNotAcceptingContractsAlert checks that the host warns when it has
+// stopped accepting contracts but still has unresolved contracts to honor,
+// and stops warning once the last of them is deleted.
+func TestHostNotAcceptingContractsAlert(t *testing.T) {
+	h := New()
+
+	if err := h.SetSettings(modules.HostSettings{AcceptingContracts: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var fcid types.FileContractID
+	if err := h.FormContract(fcid); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 0 {
+		t.Fatal("expected no alert while the host is still accepting contracts")
+	}
+
+	if err := h.SetSettings(modules.HostSettings{AcceptingContracts: false}); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 1 {
+		t.Fatalf("expected the not-accepting-contracts alert, got %d alerts", len(h.Alerts()))
+	}
+
+	if err := h.DeleteContract(fcid); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Alerts()) != 0 {
+		t.Fatal("expected the alert to clear once the last unresolved contract was deleted")
+	}
+}
+
+// TestHostRPCErrorAlerts checks that TrackRPCCall raises a warning once
+// failed calls cross the warning threshold and an error once they cross the
+// critical threshold.
+func TestHostRPCErrorAlerts(t *testing.T) {
+	h := New()
+
+	for i := 0; i < errorCallWarningThreshold-1; i++ {
+		h.TrackRPCCall(true, false)
+	}
+	if len(h.Alerts()) != 0 {
+		t.Fatal("expected no alert just below the warning threshold")
+	}
+
+	h.TrackRPCCall(true, false)
+	alerts := h.Alerts()
+	if len(alerts) != 1 || alerts[0].Severity != modules.SeverityWarning {
+		t.Fatalf("expected a single warning alert at the warning threshold, got %+v", alerts)
+	}
+
+	for i := uint64(0); i < errorCallCriticalThreshold-errorCallWarningThreshold; i++ {
+		h.TrackRPCCall(false, true)
+	}
+	alerts = h.Alerts()
+	if len(alerts) != 1 || alerts[0].Severity != modules.SeverityError {
+		t.Fatalf("expected the alert to escalate to an error at the critical threshold, got %+v", alerts)
+	}
+}
@@ -0,0 +1,197 @@
+package host
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/sync"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errNoNetAddress is returned by Announce when the host has not been
+	// told, and has no other way of knowing, the address to announce.
+	errNoNetAddress = errors.New("host cannot announce without a net address")
+
+	// errContractNotFound is returned by DeleteContract when asked to
+	// delete a contract the host has no record of.
+	errContractNotFound = errors.New("no record of that file contract")
+
+	// errContractAlreadyExists is returned by FormContract when asked to
+	// track a contract the host already has a record of.
+	errContractAlreadyExists = errors.New("contract is already tracked by the host")
+)
+
+// Ensure that Host satisfies the modules.Host interface.
+var _ modules.Host = (*Host)(nil)
+
+// Host implements the modules.Host interface, tracking the settings,
+// metrics, and unresolved contracts that its alerts are derived from.
+type Host struct {
+	settings   modules.HostSettings
+	metrics    modules.HostRPCMetrics
+	netAddress modules.NetAddress
+	contracts  map[types.FileContractID]struct{}
+
+	revenueUnresolved types.Currency
+	revenueResolved   types.Currency
+	revenueLost       types.Currency
+
+	// alerts tracks non-fatal conditions the host wants to surface to an
+	// operator, such as running low on storage or seeing a rise in failed
+	// RPC calls.
+	alerts *modules.GenericAlerter
+
+	mu sync.TryMutex
+}
+
+// New creates a new Host with default settings and no contracts.
+func New() *Host {
+	h := &Host{
+		contracts: make(map[types.FileContractID]struct{}),
+		alerts:    modules.NewAlerter("host"),
+	}
+	h.updateAlerts()
+	return h
+}
+
+// Alerts returns the alerts currently registered with the host.
+func (h *Host) Alerts() []modules.Alert {
+	return h.alerts.Alerts()
+}
+
+// Settings returns the host's settings.
+func (h *Host) Settings() modules.HostSettings {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return h.settings
+}
+
+// SetSettings sets the hosting parameters of the host, refreshing any
+// alerts that depend on them.
+func (h *Host) SetSettings(settings modules.HostSettings) error {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	h.settings = settings
+	h.updateAlerts()
+	return nil
+}
+
+// RPCMetrics returns information on the types of RPC calls that have been
+// made to the host.
+func (h *Host) RPCMetrics() modules.HostRPCMetrics {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return h.metrics
+}
+
+// Contracts returns the number of unresolved file contracts that the host
+// is responsible for.
+func (h *Host) Contracts() uint64 {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return uint64(len(h.contracts))
+}
+
+// NetAddress returns the host's network address.
+func (h *Host) NetAddress() modules.NetAddress {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return h.netAddress
+}
+
+// Capacity returns the amount of storage still available on the machine.
+func (h *Host) Capacity() uint64 {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return h.settings.RemainingStorage
+}
+
+// Revenue returns the amount of revenue that the host has lined up, the
+// amount of revenue the host has successfully captured, and the amount of
+// revenue the host has lost.
+func (h *Host) Revenue() (unresolved, resolved, lost types.Currency) {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	return h.revenueUnresolved, h.revenueResolved, h.revenueLost
+}
+
+// AnnounceAddress behaves like Announce, but allows the caller to specify
+// the address announced. Like Announce, this will cause the host to start
+// accepting contracts.
+func (h *Host) AnnounceAddress(addr modules.NetAddress) error {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	h.announceAddress(addr)
+	return nil
+}
+
+// Announce submits a host announcement to the blockchain, returning an
+// error if its external IP address is unknown. After announcing, the host
+// will begin accepting contracts.
+func (h *Host) Announce() error {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	if h.netAddress == "" {
+		return errNoNetAddress
+	}
+	h.announceAddress(h.netAddress)
+	return nil
+}
+
+// announceAddress records addr as the host's net address and marks it as
+// accepting contracts, refreshing any alerts that depend on either. The
+// caller must hold h.mu.
+func (h *Host) announceAddress(addr modules.NetAddress) {
+	h.netAddress = addr
+	h.settings.AcceptingContracts = true
+	h.updateAlerts()
+}
+
+// DeleteContract deletes a file contract. The revenue and collateral on
+// the file contract will be lost, and the data will be removed.
+func (h *Host) DeleteContract(id types.FileContractID) error {
+	lockID := h.mu.Lock()
+	defer h.mu.Unlock(lockID)
+	if _, exists := h.contracts[id]; !exists {
+		return errContractNotFound
+	}
+	delete(h.contracts, id)
+	h.updateAlerts()
+	return nil
+}
+
+// FormContract records a newly formed file contract as one the host is now
+// responsible for, refreshing any alerts that depend on the host's
+// contract count. It is called by the host's RPC dispatch once a contract
+// negotiation completes successfully.
+func (h *Host) FormContract(id types.FileContractID) error {
+	lockID := h.mu.Lock()
+	defer h.mu.Unlock(lockID)
+	if _, exists := h.contracts[id]; exists {
+		return errContractAlreadyExists
+	}
+	h.contracts[id] = struct{}{}
+	h.updateAlerts()
+	return nil
+}
+
+// Close saves the state of the host and stops its listener process.
+func (h *Host) Close() error {
+	return nil
+}
+
+// TrackRPCCall records the outcome of an RPC call, refreshing any alerts
+// that depend on the host's RPC metrics. It is called by the host's RPC
+// dispatch for every call it serves.
+func (h *Host) TrackRPCCall(errored, unrecognized bool) {
+	id := h.mu.Lock()
+	defer h.mu.Unlock(id)
+	if errored {
+		h.metrics.ErrorCalls++
+	}
+	if unrecognized {
+		h.metrics.UnrecognizedCalls++
+	}
+	h.updateAlerts()
+}
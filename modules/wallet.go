@@ -0,0 +1,24 @@
+package modules
+
+import (
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// WalletDir names the directory that contains the wallet persistence.
+const WalletDir = "wallet"
+
+// Wallet stores and manages siacoins.
+type Wallet interface {
+	// Alerter allows the wallet to be queried for any alerts it has
+	// registered, such as being locked while the consensus set is still
+	// syncing.
+	Alerter
+
+	// ReceiveConsensusSetUpdate is called by the consensus set every time
+	// the consensus set changes.
+	ReceiveConsensusSetUpdate(cc ConsensusChange)
+
+	// ReceiveTransactionPoolUpdate is called by the transaction pool every
+	// time the confirmed or unconfirmed set changes.
+	ReceiveTransactionPoolUpdate(cc ConsensusChange, unconfirmedTransactions []types.Transaction, unconfirmedSiacoinDiffs []SiacoinOutputDiff)
+}
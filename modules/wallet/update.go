@@ -1,11 +1,19 @@
 package wallet
 
 import (
+	"fmt"
+
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 )
 
+// alertIDMissingOutput returns the AlertID used to track a wallet output
+// that was referenced by a diff but is not known to the wallet.
+func alertIDMissingOutput(id types.SiacoinOutputID) modules.AlertID {
+	return modules.AlertID(fmt.Sprintf("wallet-missing-output-%s", id))
+}
+
 // applyDiff will take the output and either add or delete it from the set of
 // outputs known to the wallet. If adding is true, then new outputs will be
 // added and expired outputs will be deleted. If adding is false, then new
@@ -41,14 +49,18 @@ func (w *Wallet) applyDiff(scod modules.SiacoinOutputDiff, dir modules.DiffDirec
 		}
 		key.outputs[scod.ID] = ko
 	} else {
-		if build.DEBUG {
-			_, exists := key.outputs[scod.ID]
-			if !exists {
+		output, exists := key.outputs[scod.ID]
+		if !exists {
+			if build.DEBUG {
 				panic("trying to delete an output that doesn't exist?")
 			}
+			// In a release build there is no output to correct, so alert
+			// the operator instead of crashing on a nil dereference.
+			w.alerts.RegisterAlert(alertIDMissingOutput(scod.ID), "wallet was asked to delete an output it does not know about", "possible desync between wallet and consensus set", modules.SeverityWarning)
+			return
 		}
-
-		key.outputs[scod.ID].spendable = false
+		w.alerts.UnregisterAlert(alertIDMissingOutput(scod.ID))
+		output.spendable = false
 	}
 }
 
@@ -82,3 +94,19 @@ func (w *Wallet) ReceiveTransactionPoolUpdate(cc modules.ConsensusChange, _ []ty
 
 	w.notifySubscribers()
 }
+
+// ReceiveConsensusSetUpdate is called by the consensus set every time the
+// consensus set changes. It is used to alert the operator if the wallet is
+// locked while the consensus set is still catching up to the network, since
+// a locked wallet cannot see the outputs it controls until it is unlocked
+// and the missed diffs are replayed.
+func (w *Wallet) ReceiveConsensusSetUpdate(cc modules.ConsensusChange) {
+	id := w.mu.Lock()
+	defer w.mu.Unlock(id)
+
+	if !w.unlocked && !cc.Synced {
+		w.alerts.RegisterAlert(alertIDLockedDuringSync, "wallet is locked while the consensus set is still syncing", "the wallet will not see incoming funds until it is unlocked", modules.SeverityCritical)
+	} else {
+		w.alerts.UnregisterAlert(alertIDLockedDuringSync)
+	}
+}
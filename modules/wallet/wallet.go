@@ -0,0 +1,91 @@
+package wallet
+
+import (
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/sync"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// alertIDLockedDuringSync is the AlertID used to track the wallet being
+// locked while the consensus set is still catching up to the rest of the
+// network.
+const alertIDLockedDuringSync = modules.AlertID("wallet-locked-during-sync")
+
+// Ensure that Wallet satisfies the modules.Wallet interface.
+var _ modules.Wallet = (*Wallet)(nil)
+
+type (
+	// knownOutput tracks a siacoin output that the wallet controls the
+	// spend conditions for.
+	knownOutput struct {
+		id     types.SiacoinOutputID
+		output types.SiacoinOutput
+
+		spendable bool
+		age       int
+	}
+
+	// walletKey tracks the outputs known to the wallet for a single unlock
+	// hash.
+	walletKey struct {
+		outputs map[types.SiacoinOutputID]*knownOutput
+	}
+
+	// Wallet manages the spend conditions and known outputs for a set of
+	// keys, exposing the coins they control to the rest of Sia.
+	Wallet struct {
+		keys map[types.UnlockHash]*walletKey
+
+		unconfirmedDiffs []modules.SiacoinOutputDiff
+		age              int
+
+		// unlocked indicates whether the wallet currently has access to
+		// its keys.
+		unlocked bool
+
+		// alerts tracks non-fatal conditions the wallet wants to surface
+		// to an operator, such as being locked while the consensus set is
+		// still syncing, or being asked to update an output it does not
+		// recognize.
+		alerts *modules.GenericAlerter
+
+		// subscribers are notified whenever the wallet's set of known
+		// outputs changes.
+		subscribers []chan struct{}
+
+		mu sync.TryMutex
+	}
+)
+
+// New creates a new, locked Wallet with no keys loaded.
+func New() *Wallet {
+	return &Wallet{
+		keys:   make(map[types.UnlockHash]*walletKey),
+		alerts: modules.NewAlerter("wallet"),
+	}
+}
+
+// Alerts returns the alerts currently registered with the wallet.
+func (w *Wallet) Alerts() []modules.Alert {
+	return w.alerts.Alerts()
+}
+
+// Subscribe returns a channel that receives an empty struct every time the
+// wallet's set of known outputs changes.
+func (w *Wallet) Subscribe() <-chan struct{} {
+	c := make(chan struct{}, 1)
+	w.subscribers = append(w.subscribers, c)
+	return c
+}
+
+// notifySubscribers informs every subscriber that the wallet's state has
+// changed. Subscribers that are not ready to receive are skipped rather
+// than blocked on.
+func (w *Wallet) notifySubscribers() {
+	for _, c := range w.subscribers {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
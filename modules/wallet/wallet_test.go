@@ -0,0 +1,35 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestWalletLockedDuringSyncAlert checks that the wallet raises a critical
+// alert while it is locked and the consensus set has not finished syncing,
+// and clears the alert once either condition resolves.
+func TestWalletLockedDuringSyncAlert(t *testing.T) {
+	w := New()
+
+	w.ReceiveConsensusSetUpdate(modules.ConsensusChange{Synced: false})
+	alerts := w.Alerts()
+	if len(alerts) != 1 || alerts[0].Severity != modules.SeverityCritical {
+		t.Fatalf("expected a critical alert while locked and unsynced, got %+v", alerts)
+	}
+
+	// Unlocking the wallet should clear the alert even if the consensus
+	// set is still syncing.
+	w.unlocked = true
+	w.ReceiveConsensusSetUpdate(modules.ConsensusChange{Synced: false})
+	if len(w.Alerts()) != 0 {
+		t.Fatal("expected the alert to clear once the wallet was unlocked")
+	}
+
+	// Re-locking while synced should not raise the alert either.
+	w.unlocked = false
+	w.ReceiveConsensusSetUpdate(modules.ConsensusChange{Synced: true})
+	if len(w.Alerts()) != 0 {
+		t.Fatal("expected no alert once the consensus set has synced")
+	}
+}
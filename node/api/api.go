@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// API implements the daemon's HTTP endpoints, delegating to whichever
+// modules the node was started with. A nil module causes its endpoints to
+// report that the module is not enabled, rather than panicking.
+type API struct {
+	host     modules.Host
+	wallet   modules.Wallet
+	explorer modules.Explorer
+
+	router http.Handler
+}
+
+// New creates an API that serves the endpoints for the given modules.
+func New(host modules.Host, wallet modules.Wallet, explorer modules.Explorer) *API {
+	api := &API{
+		host:     host,
+		wallet:   wallet,
+		explorer: explorer,
+	}
+	api.router = api.buildHTTPRoutes()
+	return api
+}
+
+// ServeHTTP implements http.Handler, routing requests to the handler
+// registered for them in buildHTTPRoutes.
+func (api *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	api.router.ServeHTTP(w, req)
+}
+
+// buildHTTPRoutes registers every endpoint this package exposes with a
+// fresh httprouter.Router.
+func (api *API) buildHTTPRoutes() http.Handler {
+	router := httprouter.New()
+
+	router.GET("/daemon/alerts", api.daemonAlertsHandlerGET)
+
+	router.GET("/explorer/blocks/:height", api.explorerBlocksHandlerGET)
+	router.GET("/explorer/transactions/:id", api.explorerTransactionsHandlerGET)
+	router.GET("/explorer/unlockhash/:unlockhash", api.explorerUnlockhashHandlerGET)
+	router.GET("/explorer/siacoinoutput/:id", api.explorerSiacoinOutputHandlerGET)
+	router.GET("/explorer/filecontracts/:id", api.explorerFilecontractsHandlerGET)
+
+	return router
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/NebulousLabs/Sia/node/api"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// DaemonAlertsGet requests the /daemon/alerts resource.
+func (c *Client) DaemonAlertsGet() (dag api.DaemonAlertsGET, err error) {
+	err = c.Get("/daemon/alerts", &dag)
+	return
+}
+
+// ExplorerBlockGet requests the /explorer/blocks/:height resource.
+func (c *Client) ExplorerBlockGet(height types.BlockHeight) (ebg api.ExplorerBlockGET, err error) {
+	err = c.Get(fmt.Sprintf("/explorer/blocks/%d", height), &ebg)
+	return
+}
+
+// ExplorerTransactionGet requests the /explorer/transactions/:id resource.
+func (c *Client) ExplorerTransactionGet(id types.TransactionID) (etg api.ExplorerTransactionGET, err error) {
+	err = c.Get("/explorer/transactions/"+id.String(), &etg)
+	return
+}
+
+// ExplorerUnlockhashGet requests the /explorer/unlockhash/:unlockhash
+// resource.
+func (c *Client) ExplorerUnlockhashGet(uh types.UnlockHash) (eug api.ExplorerUnlockhashGET, err error) {
+	err = c.Get("/explorer/unlockhash/"+uh.String(), &eug)
+	return
+}
+
+// ExplorerFileContractGet requests the /explorer/filecontracts/:id
+// resource.
+func (c *Client) ExplorerFileContractGet(fcid types.FileContractID) (efg api.ExplorerFileContractGET, err error) {
+	err = c.Get("/explorer/filecontracts/"+fcid.String(), &efg)
+	return
+}
+
+// ExplorerSiacoinOutputGet requests the /explorer/siacoinoutput/:id
+// resource.
+func (c *Client) ExplorerSiacoinOutputGet(id types.SiacoinOutputID) (esg api.ExplorerSiacoinOutputGET, err error) {
+	err = c.Get("/explorer/siacoinoutput/"+id.String(), &esg)
+	return
+}
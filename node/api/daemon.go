@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// DaemonAlertsGET contains the alerts currently registered by every
+	// module that the daemon has loaded.
+	DaemonAlertsGET struct {
+		Alerts []modules.Alert `json:"alerts"`
+	}
+)
+
+// daemonAlertsHandlerGET handles the API call that returns the alerts of
+// all loaded modules.
+func (api *API) daemonAlertsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	var alerts []modules.Alert
+	if api.host != nil {
+		alerts = append(alerts, api.host.Alerts()...)
+	}
+	if api.wallet != nil {
+		alerts = append(alerts, api.wallet.Alerts()...)
+	}
+	WriteJSON(w, DaemonAlertsGET{Alerts: alerts})
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// ExplorerBlockGET contains the aggregate facts for the block at a
+	// given height, as returned by the /explorer/blocks/:height endpoint.
+	ExplorerBlockGET struct {
+		Facts modules.BlockFacts `json:"facts"`
+	}
+
+	// ExplorerTransactionGET contains the block a transaction was confirmed
+	// in and the height of that block, as returned by the
+	// /explorer/transactions/:id endpoint.
+	ExplorerTransactionGET struct {
+		Block  types.Block       `json:"block"`
+		Height types.BlockHeight `json:"height"`
+	}
+
+	// ExplorerUnlockhashGET contains the transactions that touch a given
+	// unlock hash, as returned by the /explorer/unlockhash/:unlockhash
+	// endpoint.
+	ExplorerUnlockhashGET struct {
+		Transactions []types.TransactionID `json:"transactions"`
+	}
+
+	// ExplorerFileContractGET contains a file contract's history, as
+	// returned by the /explorer/filecontracts/:id endpoint.
+	ExplorerFileContractGET struct {
+		FileContract types.FileContract    `json:"filecontract"`
+		History      []types.TransactionID `json:"history"`
+	}
+
+	// ExplorerSiacoinOutputGET contains the transactions that touch a
+	// given siacoin output, as returned by the
+	// /explorer/siacoinoutput/:id endpoint.
+	ExplorerSiacoinOutputGET struct {
+		Transactions []types.TransactionID `json:"transactions"`
+	}
+)
+
+// explorerBlocksHandlerGET handles the API call that returns the aggregate
+// facts for the block at a given height.
+func (api *API) explorerBlocksHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if api.explorer == nil {
+		WriteError(w, Error{"explorer is not enabled on this node"}, http.StatusBadRequest)
+		return
+	}
+	height, err := strconv.ParseUint(ps.ByName("height"), 10, 64)
+	if err != nil {
+		WriteError(w, Error{"error parsing height: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	facts, exists := api.explorer.BlockFacts(types.BlockHeight(height))
+	if !exists {
+		WriteError(w, Error{"explorer has not indexed a block at that height"}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, ExplorerBlockGET{Facts: facts})
+}
+
+// explorerTransactionsHandlerGET handles the API call that returns the
+// block a transaction was confirmed in and the height of that block.
+func (api *API) explorerTransactionsHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if api.explorer == nil {
+		WriteError(w, Error{"explorer is not enabled on this node"}, http.StatusBadRequest)
+		return
+	}
+	var id types.TransactionID
+	if err := id.LoadString(ps.ByName("id")); err != nil {
+		WriteError(w, Error{"error parsing id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	block, height, exists := api.explorer.Transaction(id)
+	if !exists {
+		WriteError(w, Error{"explorer has not seen a transaction with that id"}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, ExplorerTransactionGET{Block: block, Height: height})
+}
+
+// explorerUnlockhashHandlerGET handles the API call that returns the
+// transactions that have touched an unlock hash.
+func (api *API) explorerUnlockhashHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if api.explorer == nil {
+		WriteError(w, Error{"explorer is not enabled on this node"}, http.StatusBadRequest)
+		return
+	}
+	var uh types.UnlockHash
+	if err := uh.LoadString(ps.ByName("unlockhash")); err != nil {
+		WriteError(w, Error{"error parsing unlock hash: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, ExplorerUnlockhashGET{Transactions: api.explorer.UnlockHashHistory(uh)})
+}
+
+// explorerSiacoinOutputHandlerGET handles the API call that returns the
+// transactions that have touched a siacoin output.
+func (api *API) explorerSiacoinOutputHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if api.explorer == nil {
+		WriteError(w, Error{"explorer is not enabled on this node"}, http.StatusBadRequest)
+		return
+	}
+	var id types.SiacoinOutputID
+	if err := id.LoadString(ps.ByName("id")); err != nil {
+		WriteError(w, Error{"error parsing id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, ExplorerSiacoinOutputGET{Transactions: api.explorer.SiacoinOutputHistory(id)})
+}
+
+// explorerFilecontractsHandlerGET handles the API call that returns the
+// history of a file contract.
+func (api *API) explorerFilecontractsHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if api.explorer == nil {
+		WriteError(w, Error{"explorer is not enabled on this node"}, http.StatusBadRequest)
+		return
+	}
+	var fcid types.FileContractID
+	if err := fcid.LoadString(ps.ByName("id")); err != nil {
+		WriteError(w, Error{"error parsing id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	fc, history := api.explorer.FileContractHistory(fcid)
+	WriteJSON(w, ExplorerFileContractGET{FileContract: fc, History: history})
+}